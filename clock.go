@@ -0,0 +1,103 @@
+package goflakeid
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ClockPolicy controls how a Generator reacts when the system clock is
+// observed to have moved backwards relative to the last timestamp it used.
+type ClockPolicy int
+
+const (
+	// PolicyError fails the in-flight Generate call with ErrClockBackwards.
+	// This is the default and matches the generator's original behavior.
+	PolicyError ClockPolicy = iota
+
+	// PolicyWait blocks (sleeping in small increments) until the wall
+	// clock catches back up to the last timestamp used, as long as the
+	// regression is no larger than Config.MaxWait. Regressions larger
+	// than MaxWait still fail with ErrClockBackwards.
+	PolicyWait
+
+	// PolicyBorrow steals the top bit of the sequence field to act as an
+	// overflow-epoch parity bit: whenever the same logical millisecond is
+	// reused after a regression, the parity flips so IDs minted before
+	// and after the regression cannot collide. This halves the usable
+	// sequence budget per millisecond. A regression never makes Generate
+	// fail: it stays pinned to the last timestamp with the parity bit set,
+	// and further regressions while pinned just continue that sequence.
+	// Once the pinned millisecond's halved sequence budget is used up,
+	// Generate waits until the wall clock passes the pinned timestamp.
+	PolicyBorrow
+
+	// PolicyLogical advances an internal logical clock to one tick past
+	// the last timestamp used whenever a regression is observed, and uses
+	// that logical clock instead of wall time until the wall clock catches
+	// back up. IDs stay monotonic and unique without blocking.
+	PolicyLogical
+)
+
+// DefaultMaxWait is used by PolicyWait when Config.MaxWait is left zero.
+const DefaultMaxWait = 5 * 1_000_000 // 5ms, expressed in nanoseconds (time.Duration)
+
+// StateStore persists a generator's high-water timestamp mark so a
+// restarted process can detect whether the local clock has regressed
+// across the restart.
+type StateStore interface {
+	// Load returns the last saved epoch-relative millisecond timestamp.
+	// It returns (0, nil) if no state has ever been saved.
+	Load() (uint64, error)
+	// Save persists ts as the new high-water mark.
+	Save(ts uint64) error
+}
+
+// FileStateStore is the default StateStore, backed by a single file
+// holding the decimal high-water mark.
+type FileStateStore struct {
+	path string
+}
+
+// NewFileStateStore creates a FileStateStore that persists to path.
+func NewFileStateStore(path string) *FileStateStore {
+	return &FileStateStore{path: path}
+}
+
+// Load implements StateStore.
+func (f *FileStateStore) Load() (uint64, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("goflakeid: reading state file: %w", err)
+	}
+	ts, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("goflakeid: parsing state file: %w", err)
+	}
+	return ts, nil
+}
+
+// Save implements StateStore.
+func (f *FileStateStore) Save(ts uint64) error {
+	if err := os.WriteFile(f.path, []byte(strconv.FormatUint(ts, 10)), 0o644); err != nil {
+		return fmt.Errorf("goflakeid: writing state file: %w", err)
+	}
+	return nil
+}
+
+// SaveState persists the generator's current timestamp high-water mark via
+// its configured StateStore, if any. Callers running a long-lived
+// generator should call this periodically (and on shutdown) so a restart
+// can detect a clock regression across the restart; NewGenerator refuses
+// to start if the persisted mark is newer than the current time.
+func (g *Generator) SaveState() error {
+	if g.stateStore == nil {
+		return nil
+	}
+	state := g.state.Load()
+	return g.stateStore.Save(state >> 22)
+}
@@ -0,0 +1,125 @@
+package goflakeid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Comb bit widths for the region/app/machine fields packed into the 16
+// bits following the timestamp. These mirror DefaultBitLayout's
+// Region/App/Machine split (4+3+5=12 bits); the remaining 4 bits of that
+// 16-bit span hold the UUID version nibble.
+const (
+	combRegionBits  = 4
+	combAppBits     = 3
+	combMachineBits = 5
+
+	combAppShift    = combMachineBits
+	combRegionShift = combAppBits + combMachineBits
+	combVersion     = 0x8 // custom/experimental, per RFC 4122 ver 8
+	combVariantByte = 8   // index of the byte carrying the RFC 4122 variant
+)
+
+// GenerateComb produces a 128-bit, UUID-compatible value laid out as:
+//
+//	bytes  0-5 : 48-bit millisecond timestamp (epoch-relative, big-endian)
+//	byte   6   : version nibble (0x8) | high nibble of region/app/machine
+//	byte   7   : low byte of region/app/machine
+//	bytes  8-15: crypto/rand suffix, with the RFC 4122 variant bits set
+//
+// Byte-order sorting a GenerateComb value therefore agrees with generation
+// time, and the result is accepted by any column typed as a UUID (Postgres
+// uuid, SQL Server uniqueidentifier, ...) while still carrying embedded
+// Snowflake components recoverable via DecodeComb.
+func (g *Generator) GenerateComb() ([16]byte, error) {
+	var out [16]byte
+
+	now := time.Now().UnixMilli() - g.config.Epoch.UnixMilli()
+	if now < 0 {
+		return out, fmt.Errorf("%w: epoch is in the future", ErrClockBackwards)
+	}
+	if now > (1<<48)-1 {
+		return out, fmt.Errorf("timestamp exceeds 48 bits")
+	}
+
+	var tsBuf [8]byte
+	binary.BigEndian.PutUint64(tsBuf[:], uint64(now))
+	copy(out[0:6], tsBuf[2:8])
+
+	packed := uint16(g.config.RegionID)<<combRegionShift |
+		uint16(g.config.AppID)<<combAppShift |
+		uint16(g.config.MachineID)
+	out[6] = (combVersion << 4) | byte(packed>>8&0x0F)
+	out[7] = byte(packed)
+
+	if _, err := rand.Read(out[8:16]); err != nil {
+		return out, fmt.Errorf("goflakeid: failed to read random suffix: %w", err)
+	}
+	out[combVariantByte] = (out[combVariantByte] & 0x3F) | 0x80
+
+	return out, nil
+}
+
+// DecodeComb recovers the timestamp and region/app/machine fields embedded
+// in a value produced by g.GenerateComb. The timestamp is interpreted
+// relative to g's configured Epoch, mirroring how GenerateComb encoded it
+// — decoding against a different generator's epoch would silently shift
+// the recovered time by the epochs' offset. The Sequence field is always
+// zero, since GenerateComb does not carry one; uniqueness instead comes
+// from the random suffix.
+func (g *Generator) DecodeComb(b [16]byte) (Components, error) {
+	var tsBuf [8]byte
+	copy(tsBuf[2:8], b[0:6])
+	ms := binary.BigEndian.Uint64(tsBuf[:])
+
+	if b[6]>>4 != combVersion {
+		return Components{}, fmt.Errorf("%w: unexpected version nibble %#x", ErrInvalidEncoding, b[6]>>4)
+	}
+
+	packed := uint16(b[6]&0x0F)<<8 | uint16(b[7])
+	machineID := uint8(packed & ((1 << combMachineBits) - 1))
+	appID := uint8((packed >> combAppShift) & ((1 << combAppBits) - 1))
+	regionID := uint16((packed >> combRegionShift) & ((1 << combRegionBits) - 1))
+
+	return Components{
+		Timestamp: g.config.Epoch.Add(time.Duration(ms) * time.Millisecond),
+		RegionID:  regionID,
+		AppID:     appID,
+		MachineID: machineID,
+	}, nil
+}
+
+// CombString formats a GenerateComb value as a canonical
+// xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx UUID string.
+func CombString(b [16]byte) string {
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:36], b[10:16])
+	return string(buf)
+}
+
+// ParseComb parses a canonical UUID string produced by CombString back
+// into its raw 16 bytes.
+func ParseComb(s string) ([16]byte, error) {
+	var out [16]byte
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return out, fmt.Errorf("%w: malformed comb string %q", ErrInvalidEncoding, s)
+	}
+	hexPart := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36]
+	decoded, err := hex.DecodeString(hexPart)
+	if err != nil {
+		return out, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	copy(out[:], decoded)
+	return out, nil
+}
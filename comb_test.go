@@ -0,0 +1,33 @@
+package goflakeid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGenerateCombDecodeCombUsesGeneratorEpoch(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	g, err := NewGenerator(*NewConfig(3, 2, 7).WithEpoch(epoch))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+
+	before := time.Now()
+	comb, err := g.GenerateComb()
+	if err != nil {
+		t.Fatalf("GenerateComb: %v", err)
+	}
+	after := time.Now()
+
+	c, err := g.DecodeComb(comb)
+	if err != nil {
+		t.Fatalf("DecodeComb: %v", err)
+	}
+
+	if c.Timestamp.Before(before.Add(-time.Second)) || c.Timestamp.After(after.Add(time.Second)) {
+		t.Fatalf("decoded timestamp %v not within [%v, %v]", c.Timestamp, before, after)
+	}
+	if c.RegionID != 3 || c.AppID != 2 || c.MachineID != 7 {
+		t.Fatalf("decoded components = %+v, want region=3 app=2 machine=7", c)
+	}
+}
@@ -0,0 +1,247 @@
+package goflakeid
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ID is a string-encodable wrapper around a generated uint64 value. It
+// implements the standard marshaling interfaces so IDs round-trip cleanly
+// through JSON, text-based formats, binary wire protocols, and database
+// columns without losing the original 64-bit value.
+type ID uint64
+
+// Alphabets used by the fixed-width string encodings. Each alphabet is
+// listed in ascending character order so that, for a fixed width, string
+// comparison of encoded values agrees with numeric comparison of the
+// underlying uint64.
+const (
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	base58Alphabet    = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	base62Alphabet    = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	base32Width = 13 // ceil(64/5), sortable fixed width for Crockford Base32
+	base58Width = 11 // 58^11 > 2^64
+	// base62Width is deliberately one character wider than the 11 a tight
+	// packing would need (62^11 > 2^64 already): every Base58 character is
+	// also a valid Base62 character, so an 11-wide Base62 string would
+	// often also parse as (a different-valued) Base58 string, and length
+	// alone couldn't tell ParseID which format was meant. Widening to 12
+	// makes the three encodings' lengths mutually exclusive.
+	base62Width = 12
+)
+
+var (
+	base58 = new(big.Int).SetUint64(58)
+	base62 = new(big.Int).SetUint64(62)
+
+	// ErrInvalidEncoding is returned when a string does not decode to a
+	// valid ID under any supported format.
+	ErrInvalidEncoding = fmt.Errorf("goflakeid: invalid encoded id")
+)
+
+// Base32 returns the Crockford Base32 encoding of id: a fixed-width,
+// lexicographically sortable string safe for URLs and human transcription.
+func (id ID) Base32() string {
+	return encodeFixedWidth(uint64(id), crockfordAlphabet, base32Width)
+}
+
+// Base58 returns the Bitcoin-alphabet Base58 encoding of id.
+func (id ID) Base58() string {
+	return encodeFixedWidthBig(uint64(id), base58Alphabet, base58, base58Width)
+}
+
+// Base62 returns the Base62 (0-9A-Za-z) encoding of id.
+func (id ID) Base62() string {
+	return encodeFixedWidthBig(uint64(id), base62Alphabet, base62, base62Width)
+}
+
+// String implements fmt.Stringer, returning the Base32 form, which is the
+// default wire representation used by MarshalText and friends.
+func (id ID) String() string {
+	return id.Base32()
+}
+
+// ParseID decodes s into an ID, auto-detecting the format (Base32, Base58,
+// or Base62) from its length and alphabet. Round-tripping through any of
+// the supported formats preserves the original 64-bit value.
+//
+// Detection relies on base32Width, base58Width, and base62Width being
+// mutually exclusive: since every Base58 character is also a valid Base62
+// character, two formats sharing a width would make some valid strings of
+// that width ambiguous (decodable under either alphabet, to two different
+// values) with no way to tell which was meant from the string alone.
+func ParseID(s string) (ID, error) {
+	switch len(s) {
+	case base32Width:
+		if v, ok := decodeCrockford(s); ok {
+			return ID(v), nil
+		}
+	case base58Width:
+		if v, ok := decodeFixedWidth(s, base58Alphabet, base58); ok {
+			return ID(v), nil
+		}
+	case base62Width:
+		if v, ok := decodeFixedWidth(s, base62Alphabet, base62); ok {
+			return ID(v), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrInvalidEncoding, s)
+}
+
+// encodeFixedWidth encodes n using alphabet, left-padding with the
+// alphabet's first (lowest) character to reach width.
+func encodeFixedWidth(n uint64, alphabet string, width int) string {
+	base := uint64(len(alphabet))
+	buf := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		buf[i] = alphabet[n%base]
+		n /= base
+	}
+	return string(buf)
+}
+
+// encodeFixedWidthBig is like encodeFixedWidth but does its division with
+// math/big.
+func encodeFixedWidthBig(n uint64, alphabet string, base *big.Int, width int) string {
+	v := new(big.Int).SetUint64(n)
+	zero := new(big.Int)
+	mod := new(big.Int)
+	buf := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		if v.Cmp(zero) == 0 {
+			buf[i] = alphabet[0]
+			continue
+		}
+		v.DivMod(v, base, mod)
+		buf[i] = alphabet[mod.Int64()]
+	}
+	return string(buf)
+}
+
+// decodeCrockford decodes a Crockford Base32 string, normalizing case and
+// the I/L/O look-alike substitutions the Crockford spec allows.
+func decodeCrockford(s string) (uint64, bool) {
+	s = strings.ToUpper(s)
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case 'I', 'L':
+			c = '1'
+		case 'O':
+			c = '0'
+		}
+		idx := strings.IndexByte(crockfordAlphabet, c)
+		if idx < 0 {
+			return 0, false
+		}
+		if n>>59 != 0 {
+			return 0, false // value does not fit in 64 bits
+		}
+		n = n*32 + uint64(idx)
+	}
+	return n, true
+}
+
+// decodeFixedWidth decodes s using alphabet/base, rejecting any character
+// not present in alphabet so callers can use it to test format membership.
+func decodeFixedWidth(s string, alphabet string, base *big.Int) (uint64, bool) {
+	v := new(big.Int)
+	for i := 0; i < len(s); i++ {
+		idx := strings.IndexByte(alphabet, s[i])
+		if idx < 0 {
+			return 0, false
+		}
+		v.Mul(v, base)
+		v.Add(v, big.NewInt(int64(idx)))
+	}
+	if !v.IsUint64() {
+		return 0, false
+	}
+	return v.Uint64(), true
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *ID) UnmarshalText(text []byte) error {
+	parsed, err := ParseID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the ID as a quoted string
+// so it survives round trips through JavaScript's float64 numbers.
+func (id ID) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + id.String() + `"`), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (id *ID) UnmarshalJSON(data []byte) error {
+	s := string(bytes.Trim(data, `"`))
+	parsed, err := ParseID(s)
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, returning the ID as 8
+// big-endian bytes so the encoding remains sortable.
+func (id ID) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(id))
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (id *ID) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return fmt.Errorf("%w: expected 8 bytes, got %d", ErrInvalidEncoding, len(data))
+	}
+	*id = ID(binary.BigEndian.Uint64(data))
+	return nil
+}
+
+// Value implements driver.Valuer, storing the ID as its Base32 string form.
+func (id ID) Value() (driver.Value, error) {
+	return id.String(), nil
+}
+
+// Scan implements sql.Scanner, accepting the string/[]byte forms produced
+// by Value as well as a raw int64/uint64 for columns storing the integer.
+func (id *ID) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		parsed, err := ParseID(v)
+		if err != nil {
+			return err
+		}
+		*id = parsed
+	case []byte:
+		parsed, err := ParseID(string(v))
+		if err != nil {
+			return err
+		}
+		*id = parsed
+	case int64:
+		*id = ID(uint64(v))
+	case uint64:
+		*id = ID(v)
+	default:
+		return fmt.Errorf("%w: unsupported Scan type %T", ErrInvalidEncoding, src)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package goflakeid
+
+import "testing"
+
+func TestParseIDRoundTrip(t *testing.T) {
+	values := []uint64{
+		0, 1, 42, 1023, 1 << 20, 1<<42 - 1,
+		13576610436048252185, 6535699879636853907,
+		18446744073709551615, // max uint64
+	}
+	for step := uint64(0); step < 5000; step++ {
+		values = append(values, step*982451653+1)
+	}
+
+	for _, v := range values {
+		id := ID(v)
+		for name, s := range map[string]string{
+			"base32": id.Base32(),
+			"base58": id.Base58(),
+			"base62": id.Base62(),
+		} {
+			got, err := ParseID(s)
+			if err != nil {
+				t.Fatalf("ParseID(%s %q) for value %d: %v", name, s, v, err)
+			}
+			if uint64(got) != v {
+				t.Fatalf("ParseID(%s %q) = %d, want %d (original value %d)", name, s, uint64(got), v, v)
+			}
+		}
+	}
+}
+
+func TestParseIDAmbiguousWidthDisambiguates(t *testing.T) {
+	// Regression test: when base58Width and base62Width were both 11, a
+	// naive first-alphabet-wins ParseID silently misdecoded many Base62
+	// strings as Base58. Sweep enough values to catch that class of bug again.
+	for i := uint64(0); i < 20000; i++ {
+		v := i * 982451653
+		id := ID(v)
+		s := id.Base62()
+		got, err := ParseID(s)
+		if err != nil {
+			t.Fatalf("ParseID(%q) for base62 value %d: %v", s, v, err)
+		}
+		if uint64(got) != v {
+			t.Fatalf("ParseID(%q) = %d, want %d", s, uint64(got), v)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package index_test
+
+import (
+	"fmt"
+	"time"
+
+	goflakeid "github.com/capy-engineer/go-flakeid"
+	"github.com/capy-engineer/go-flakeid/index"
+)
+
+// This example shows the intended use of TimeRange: compute [lo, hi] once
+// from a time window and a generator's bit layout/epoch, then use the
+// bounds directly as a `WHERE id BETWEEN ? AND ?` predicate instead of
+// scanning every row to decode its timestamp.
+func Example() {
+	layout := goflakeid.DefaultBitLayout()
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	idx, err := index.NewTimeIndex(layout, epoch)
+	if err != nil {
+		panic(err)
+	}
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	lo, hi := idx.TimeRange(start, end)
+
+	const query = `SELECT * FROM events WHERE id BETWEEN ? AND ?`
+	fmt.Println(query)
+	fmt.Println(lo < hi)
+	// Output:
+	// SELECT * FROM events WHERE id BETWEEN ? AND ?
+	// true
+}
@@ -0,0 +1,85 @@
+// Package index supports range-querying a collection of goflakeid IDs by
+// time window without decoding each one individually. Because a
+// Snowflake-style ID's timestamp sits in its high bits, every ID
+// generated during [t0, t1] falls between two bounding IDs that can be
+// computed once from the bit layout and epoch alone; that range can then
+// be used as a `WHERE id BETWEEN ? AND ?` SQL predicate or to binary-search
+// a sorted slice, without touching any individual ID's bits.
+package index
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	goflakeid "github.com/capy-engineer/go-flakeid"
+)
+
+// TimeIndex computes ID bounds for a time window given a fixed bit layout
+// and epoch, matching how goflakeid.Generator packs its IDs.
+type TimeIndex struct {
+	layout goflakeid.BitLayout
+	epoch  time.Time
+	shift  uint8
+}
+
+// NewTimeIndex builds a TimeIndex for the given bit layout and epoch. Use
+// the same values the generator that produced the IDs was configured
+// with.
+func NewTimeIndex(layout goflakeid.BitLayout, epoch time.Time) (*TimeIndex, error) {
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+	shift := layout.RegionBits + layout.AppBits + layout.MachineBits + layout.SequenceBits
+	return &TimeIndex{layout: layout, epoch: epoch, shift: shift}, nil
+}
+
+// TimeRange returns the inclusive [lo, hi] ID bounds whose timestamp field
+// falls within [t0, t1]: lo is t0's timestamp with all lower bits zeroed,
+// hi is t1's timestamp with all lower bits set.
+func (t *TimeIndex) TimeRange(t0, t1 time.Time) (lo, hi uint64) {
+	loMS := uint64(t0.Sub(t.epoch) / time.Millisecond)
+	hiMS := uint64(t1.Sub(t.epoch) / time.Millisecond)
+	mask := (uint64(1) << t.shift) - 1
+	lo = loMS << t.shift
+	hi = (hiMS << t.shift) | mask
+	return lo, hi
+}
+
+// Filter returns the subset of ids whose timestamp field falls within
+// [t0, t1].
+func (t *TimeIndex) Filter(ids []uint64, t0, t1 time.Time) []uint64 {
+	lo, hi := t.TimeRange(t0, t1)
+	out := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if id >= lo && id <= hi {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// Scan reads a stream of 8-byte big-endian IDs (the wire format produced
+// by goflakeid.ID.MarshalBinary) from r and sends the ones falling within
+// [t0, t1] to out, closing out once r is exhausted or an error occurs.
+func (t *TimeIndex) Scan(r io.Reader, t0, t1 time.Time, out chan<- uint64) error {
+	defer close(out)
+
+	lo, hi := t.TimeRange(t0, t1)
+	br := bufio.NewReader(r)
+	var buf [8]byte
+	for {
+		if _, err := io.ReadFull(br, buf[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("goflakeid/index: reading id stream: %w", err)
+		}
+		id := binary.BigEndian.Uint64(buf[:])
+		if id >= lo && id <= hi {
+			out <- id
+		}
+	}
+}
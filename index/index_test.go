@@ -0,0 +1,111 @@
+package index
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	goflakeid "github.com/capy-engineer/go-flakeid"
+)
+
+func TestTimeRangeAgreesWithDecodedTimestamps(t *testing.T) {
+	layout := goflakeid.DefaultBitLayout()
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	g, err := goflakeid.NewGenerator(*goflakeid.NewConfig(1, 1, 1).
+		WithBitLayout(layout).
+		WithEpoch(epoch))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+
+	idx, err := NewTimeIndex(layout, epoch)
+	if err != nil {
+		t.Fatalf("NewTimeIndex: %v", err)
+	}
+
+	before := time.Now()
+	var ids []uint64
+	for i := 0; i < 50; i++ {
+		id, err := g.Generate()
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	after := time.Now()
+
+	lo, hi := idx.TimeRange(before, after)
+	for _, id := range ids {
+		if id < lo || id > hi {
+			t.Fatalf("id %d falls outside TimeRange bounds [%d, %d]", id, lo, hi)
+		}
+	}
+
+	filtered := idx.Filter(ids, before, after)
+	if len(filtered) != len(ids) {
+		t.Fatalf("Filter kept %d of %d ids generated within the window", len(filtered), len(ids))
+	}
+
+	// A window entirely before generation started must exclude every id.
+	past := before.Add(-time.Hour)
+	if got := idx.Filter(ids, past, past.Add(time.Minute)); len(got) != 0 {
+		t.Fatalf("Filter on a disjoint window returned %d ids, want 0", len(got))
+	}
+
+	// Bounds should agree with what Generator.Decode reports for each id.
+	for _, id := range ids {
+		c := g.Decode(id)
+		if c.Timestamp.Before(before.Add(-time.Millisecond)) || c.Timestamp.After(after.Add(time.Millisecond)) {
+			t.Fatalf("decoded timestamp %v for id %d outside [%v, %v]", c.Timestamp, id, before, after)
+		}
+	}
+}
+
+func TestScanFiltersBinaryIDStream(t *testing.T) {
+	layout := goflakeid.DefaultBitLayout()
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	g, err := goflakeid.NewGenerator(*goflakeid.NewConfig(1, 1, 1).
+		WithBitLayout(layout).
+		WithEpoch(epoch))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+	idx, err := NewTimeIndex(layout, epoch)
+	if err != nil {
+		t.Fatalf("NewTimeIndex: %v", err)
+	}
+
+	before := time.Now()
+	var buf bytes.Buffer
+	var want []uint64
+	for i := 0; i < 10; i++ {
+		id, err := g.Generate()
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		want = append(want, id)
+		b, err := goflakeid.ID(id).MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary: %v", err)
+		}
+		buf.Write(b)
+	}
+	after := time.Now()
+
+	out := make(chan uint64)
+	errCh := make(chan error, 1)
+	go func() { errCh <- idx.Scan(&buf, before, after, out) }()
+
+	var got []uint64
+	for id := range out {
+		got = append(got, id)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Scan delivered %d ids, want %d", len(got), len(want))
+	}
+}
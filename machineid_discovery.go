@@ -0,0 +1,102 @@
+package goflakeid
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KubernetesMachineID derives a machine ID from the ordinal suffix of a
+// StatefulSet pod's hostname (e.g. "myapp-3" -> 3), which Kubernetes
+// guarantees is stable and unique within that StatefulSet. Unlike
+// DefaultMachineIDGenerator's hash, this does not collide across
+// StatefulSets that happen to share an ordinal, as long as each is
+// configured with a distinct RegionID/AppID. It returns an error if
+// $HOSTNAME does not end in "-<digits>".
+func KubernetesMachineID() (uint8, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return 0, fmt.Errorf("goflakeid: reading hostname: %w", err)
+	}
+
+	idx := strings.LastIndex(hostname, "-")
+	if idx < 0 || idx == len(hostname)-1 {
+		return 0, fmt.Errorf("goflakeid: hostname %q is not a StatefulSet pod name", hostname)
+	}
+
+	ordinal, err := strconv.ParseUint(hostname[idx+1:], 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("goflakeid: hostname %q has no numeric ordinal suffix: %w", hostname, err)
+	}
+	return uint8(ordinal), nil
+}
+
+// metadataEndpoints lists the well-known cloud instance-metadata URLs that
+// return a per-instance identifier, keyed by the header needed to pass
+// their "are you really a VM on this cloud" check.
+var metadataEndpoints = []struct {
+	url    string
+	header [2]string
+}{
+	{"http://169.254.169.254/latest/meta-data/instance-id", [2]string{"", ""}},                        // AWS
+	{"http://169.254.169.254/computeMetadata/v1/instance/id", [2]string{"Metadata-Flavor", "Google"}}, // GCP
+}
+
+// CloudMetadataMachineID reads the AWS, then the GCP, instance-metadata
+// endpoint (the first that answers 200 OK wins) for this instance's unique ID and folds it
+// down to a machine ID via the same hash DefaultMachineIDGenerator uses
+// for hostnames.
+//
+// This is best-effort, not collision-free: it does not coordinate with
+// any other instance, so two instances in a fleet larger than the
+// machine ID space (or simply unlucky) can hash to the same id — the
+// exact failure mode DefaultMachineIDGenerator has for hostnames. Use a
+// MachineIDProvider (Redis/etcd/ZooKeeper-backed) instead for fleets
+// where a collision would actually matter.
+func CloudMetadataMachineID() (uint8, error) {
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	var lastErr error
+	for _, ep := range metadataEndpoints {
+		req, err := http.NewRequest(http.MethodGet, ep.url, nil)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if ep.header[0] != "" {
+			req.Header.Set(ep.header[0], ep.header[1])
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != http.StatusOK {
+			lastErr = fmt.Errorf("goflakeid: %s returned %s", ep.url, resp.Status)
+			continue
+		}
+
+		return hashMachineID(string(body)), nil
+	}
+
+	return 0, fmt.Errorf("goflakeid: no cloud metadata endpoint responded: %w", lastErr)
+}
+
+// hashMachineID folds an arbitrary identifier string down to 5 bits using
+// the same hash as DefaultMachineIDGenerator. Like that hash, it is
+// collision-prone over an unbounded input space (see CloudMetadataMachineID's
+// doc comment); it is not a substitute for a coordinating MachineIDProvider.
+func hashMachineID(s string) uint8 {
+	hash := uint8(0)
+	for _, char := range s {
+		hash = hash*31 + uint8(char)
+	}
+	return hash & 0x1F
+}
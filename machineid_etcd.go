@@ -0,0 +1,144 @@
+package goflakeid
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// EtcdMachineIDProvider allocates a machine ID using an etcd v3 lease plus
+// a put-if-absent transaction on /goflakeid/machines/<id>, one candidate
+// id at a time. It talks to etcd's JSON gRPC-gateway over plain
+// net/http rather than the etcd client module, so it carries no
+// third-party dependency.
+type EtcdMachineIDProvider struct {
+	Endpoint string        // e.g. "http://127.0.0.1:2379"
+	TTL      time.Duration // lease TTL; defaults to 30s
+
+	client  *http.Client
+	leaseID string
+}
+
+func (p *EtcdMachineIDProvider) key(id int) string {
+	return fmt.Sprintf("/goflakeid/machines/%d", id)
+}
+
+// Acquire implements MachineIDProvider.
+func (p *EtcdMachineIDProvider) Acquire(ctx context.Context, bits uint8) (uint8, func(), error) {
+	if p.TTL == 0 {
+		p.TTL = 30 * time.Second
+	}
+	if p.client == nil {
+		p.client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	leaseID, err := p.grantLease(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	p.leaseID = leaseID
+
+	limit := int(1) << bits
+	for id := 0; id < limit; id++ {
+		key := p.key(id)
+		won, err := p.putIfAbsent(ctx, key, leaseID)
+		if err != nil {
+			return 0, nil, err
+		}
+		if won {
+			release := func() { p.revokeLease(context.Background(), leaseID) }
+			return uint8(id), release, nil
+		}
+	}
+
+	p.revokeLease(ctx, leaseID)
+	return 0, nil, fmt.Errorf("goflakeid: no free machine id in [0, %d) under /goflakeid/machines", limit)
+}
+
+// RenewEvery implements LeaseRenewer.
+func (p *EtcdMachineIDProvider) RenewEvery() time.Duration {
+	return p.TTL / 3
+}
+
+// Renew implements LeaseRenewer, keeping the lease alive via keepalive.
+func (p *EtcdMachineIDProvider) Renew(ctx context.Context) error {
+	body, _ := json.Marshal(map[string]string{"ID": p.leaseID})
+	_, err := p.post(ctx, "/v3/lease/keepalive", body)
+	return err
+}
+
+func (p *EtcdMachineIDProvider) grantLease(ctx context.Context) (string, error) {
+	body, _ := json.Marshal(map[string]int64{"TTL": int64(p.TTL.Seconds())})
+	resp, err := p.post(ctx, "/v3/lease/grant", body)
+	if err != nil {
+		return "", err
+	}
+	var out struct {
+		ID string `json:"ID"`
+	}
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return "", fmt.Errorf("goflakeid: decoding lease grant response: %w", err)
+	}
+	return out.ID, nil
+}
+
+func (p *EtcdMachineIDProvider) revokeLease(ctx context.Context, leaseID string) {
+	body, _ := json.Marshal(map[string]string{"ID": leaseID})
+	p.post(ctx, "/v3/lease/revoke", body)
+}
+
+// putIfAbsent performs a transaction that only puts key (leased to
+// leaseID) if it does not already exist, reporting whether it won.
+func (p *EtcdMachineIDProvider) putIfAbsent(ctx context.Context, key, leaseID string) (bool, error) {
+	encodedKey := base64.StdEncoding.EncodeToString([]byte(key))
+	encodedVal := base64.StdEncoding.EncodeToString([]byte(leaseID))
+
+	txn := map[string]any{
+		"compare": []map[string]any{
+			{"key": encodedKey, "target": "CREATE", "create_revision": "0"},
+		},
+		"success": []map[string]any{
+			{"requestPut": map[string]any{"key": encodedKey, "value": encodedVal, "lease": leaseID}},
+		},
+	}
+	body, _ := json.Marshal(txn)
+
+	resp, err := p.post(ctx, "/v3/kv/txn", body)
+	if err != nil {
+		return false, err
+	}
+	var out struct {
+		Succeeded bool `json:"succeeded"`
+	}
+	if err := json.Unmarshal(resp, &out); err != nil {
+		return false, fmt.Errorf("goflakeid: decoding txn response: %w", err)
+	}
+	return out.Succeeded, nil
+}
+
+func (p *EtcdMachineIDProvider) post(ctx context.Context, path string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.Endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("goflakeid: building etcd request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("goflakeid: calling etcd %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return nil, fmt.Errorf("goflakeid: reading etcd response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("goflakeid: etcd %s returned %s: %s", path, resp.Status, buf.String())
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,104 @@
+package goflakeid
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MachineIDProvider coordinates machine ID allocation across a fleet, so
+// that DefaultMachineIDGenerator's best-effort hostname/MAC hash — which
+// collides badly across replicas whose hostnames share an ordinal suffix,
+// e.g. Kubernetes StatefulSet pods in different namespaces — can be
+// replaced with a provider backed by a shared coordination service.
+type MachineIDProvider interface {
+	// Acquire reserves a machine ID in [0, 1<<bits) for the lifetime of
+	// the process and returns a release func to give it back. Acquire
+	// should block only long enough to perform the coordination
+	// round-trip; ctx governs that round-trip, not the lease lifetime.
+	Acquire(ctx context.Context, bits uint8) (id uint8, release func(), err error)
+}
+
+// LeaseRenewer is implemented by providers whose Acquire grants a
+// time-limited lease that must be periodically renewed to stay valid.
+// NewGenerator starts a background goroutine that calls Renew before the
+// lease's TTL expires for as long as the generator is open; a renewal
+// failure is surfaced on Generator.Err rather than panicking or silently
+// losing the machine ID.
+type LeaseRenewer interface {
+	// RenewEvery reports how often Renew should be called.
+	RenewEvery() time.Duration
+	// Renew extends the current lease.
+	Renew(ctx context.Context) error
+}
+
+// acquireMachineID runs provider.Acquire and, if provider also implements
+// LeaseRenewer, starts the background renewal goroutine that feeds the
+// returned errCh on fatal lease loss. It is called from NewGenerator
+// before the Generator itself exists, so it returns plain values rather
+// than mutating a receiver.
+func acquireMachineID(provider MachineIDProvider, bits uint8) (id uint8, release func(), cancel context.CancelFunc, errCh chan error, err error) {
+	id, release, err = provider.Acquire(context.Background(), bits)
+	if err != nil {
+		return 0, nil, nil, nil, fmt.Errorf("goflakeid: acquiring machine id: %w", err)
+	}
+
+	renewer, ok := provider.(LeaseRenewer)
+	if !ok {
+		return id, release, nil, nil, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh = make(chan error, 1)
+
+	go func() {
+		ticker := time.NewTicker(renewer.RenewEvery())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := renewer.Renew(ctx); err != nil {
+					select {
+					case errCh <- fmt.Errorf("goflakeid: renewing machine id lease: %w", err):
+					default:
+					}
+					return
+				}
+			}
+		}
+	}()
+
+	return id, release, cancel, errCh, nil
+}
+
+// Err returns a channel that receives a fatal error if the generator's
+// machine ID coordination lease is lost and could not be renewed. It
+// returns nil if the generator was not configured with a
+// MachineIDProvider that implements LeaseRenewer. A caller that receives
+// from this channel should stop using the generator, since its machine ID
+// may now be held by another process.
+func (g *Generator) Err() <-chan error {
+	return g.errCh
+}
+
+// Close releases the generator's machine ID lease, if it holds one
+// acquired via a MachineIDProvider, and stops the lease renewal goroutine.
+// It is safe to call Close more than once and on generators that were not
+// configured with a MachineIDProvider.
+func (g *Generator) Close() error {
+	var err error
+	g.closeOnce.Do(func() {
+		if g.leaseCancel != nil {
+			g.leaseCancel()
+		}
+		if g.leaseRelease != nil {
+			g.leaseRelease()
+		}
+		if g.stateStore != nil {
+			err = g.SaveState()
+		}
+	})
+	return err
+}
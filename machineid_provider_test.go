@@ -0,0 +1,137 @@
+package goflakeid
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeLeaseProvider is a MachineIDProvider + LeaseRenewer test double that
+// exercises acquireMachineID's renewal loop and error surfacing without a
+// real Redis/etcd/ZooKeeper server.
+type fakeLeaseProvider struct {
+	id         uint8
+	renewEvery time.Duration
+	renewErr   error
+	released   chan struct{}
+	renewed    chan struct{}
+}
+
+func newFakeLeaseProvider() *fakeLeaseProvider {
+	return &fakeLeaseProvider{
+		renewEvery: 5 * time.Millisecond,
+		released:   make(chan struct{}),
+		renewed:    make(chan struct{}, 8),
+	}
+}
+
+func (f *fakeLeaseProvider) Acquire(ctx context.Context, bits uint8) (uint8, func(), error) {
+	return f.id, func() { close(f.released) }, nil
+}
+
+func (f *fakeLeaseProvider) RenewEvery() time.Duration { return f.renewEvery }
+
+func (f *fakeLeaseProvider) Renew(ctx context.Context) error {
+	select {
+	case f.renewed <- struct{}{}:
+	default:
+	}
+	return f.renewErr
+}
+
+func TestAcquireMachineIDRenewsOnSchedule(t *testing.T) {
+	provider := newFakeLeaseProvider()
+	provider.id = 7
+
+	id, release, cancel, errCh, err := acquireMachineID(provider, 5)
+	if err != nil {
+		t.Fatalf("acquireMachineID: %v", err)
+	}
+	defer cancel()
+	if id != 7 {
+		t.Fatalf("id = %d, want 7", id)
+	}
+	if errCh == nil {
+		t.Fatalf("errCh is nil, want a channel since fakeLeaseProvider implements LeaseRenewer")
+	}
+
+	select {
+	case <-provider.renewed:
+	case <-time.After(time.Second):
+		t.Fatalf("Renew was not called within 1s of RenewEvery=%s", provider.renewEvery)
+	}
+
+	cancel()
+	release()
+	select {
+	case <-provider.released:
+	case <-time.After(time.Second):
+		t.Fatalf("release func was not invoked")
+	}
+}
+
+func TestAcquireMachineIDSurfacesRenewalFailure(t *testing.T) {
+	provider := newFakeLeaseProvider()
+	provider.renewErr = errors.New("lease lost")
+
+	_, release, cancel, errCh, err := acquireMachineID(provider, 5)
+	if err != nil {
+		t.Fatalf("acquireMachineID: %v", err)
+	}
+	defer release()
+	defer cancel()
+
+	select {
+	case err := <-errCh:
+		if err == nil {
+			t.Fatalf("errCh delivered a nil error")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("errCh did not receive the renewal failure within 1s")
+	}
+}
+
+func TestGeneratorErrSurfacesLeaseLoss(t *testing.T) {
+	provider := newFakeLeaseProvider()
+	provider.renewErr = errors.New("lease lost")
+
+	g, err := NewGenerator(*NewConfig(1, 1, 1).WithMachineIDProvider(provider))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+	defer g.Close()
+
+	select {
+	case err := <-g.Err():
+		if err == nil {
+			t.Fatalf("Err() delivered a nil error")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Err() did not receive the renewal failure within 1s")
+	}
+}
+
+func TestGeneratorCloseReleasesLease(t *testing.T) {
+	provider := newFakeLeaseProvider()
+
+	g, err := NewGenerator(*NewConfig(1, 1, 1).WithMachineIDProvider(provider))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+
+	if err := g.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	select {
+	case <-provider.released:
+	case <-time.After(time.Second):
+		t.Fatalf("Close did not invoke the provider's release func")
+	}
+
+	// Close must be idempotent.
+	if err := g.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package goflakeid
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// RedisMachineIDProvider allocates a machine ID by racing SETNX against
+// every candidate id in [0, 1<<bits), holding the winning key alive with a
+// heartbeat TTL refresh. It speaks RESP directly over a single TCP
+// connection rather than depending on a Redis client library, in keeping
+// with the package's zero-dependency policy.
+//
+// The connection is shared between the background lease-renewal goroutine
+// (Renew, called on a ticker by acquireMachineID) and whatever goroutine
+// calls the release func returned by Acquire (del, called from
+// Generator.Close); connMu serializes their command round-trips so a
+// renewal in flight can't interleave its RESP frames with a concurrent
+// DEL.
+type RedisMachineIDProvider struct {
+	Addr      string        // host:port of the Redis server
+	KeyPrefix string        // defaults to "goflakeid:machine:"
+	TTL       time.Duration // lease TTL; defaults to 30s
+
+	conn   net.Conn
+	connMu sync.Mutex
+	key    string
+}
+
+// Acquire implements MachineIDProvider.
+func (p *RedisMachineIDProvider) Acquire(ctx context.Context, bits uint8) (uint8, func(), error) {
+	if p.KeyPrefix == "" {
+		p.KeyPrefix = "goflakeid:machine:"
+	}
+	if p.TTL == 0 {
+		p.TTL = 30 * time.Second
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", p.Addr)
+	if err != nil {
+		return 0, nil, fmt.Errorf("goflakeid: dialing redis: %w", err)
+	}
+	p.conn = conn
+
+	limit := int(1) << bits
+	for id := 0; id < limit; id++ {
+		key := fmt.Sprintf("%s%d", p.KeyPrefix, id)
+		ok, err := p.setNX(key, p.TTL)
+		if err != nil {
+			conn.Close()
+			return 0, nil, err
+		}
+		if ok {
+			p.key = key
+			release := func() { p.del(key); conn.Close() }
+			return uint8(id), release, nil
+		}
+	}
+
+	conn.Close()
+	return 0, nil, fmt.Errorf("goflakeid: no free machine id in [0, %d) under %s*", limit, p.KeyPrefix)
+}
+
+// RenewEvery implements LeaseRenewer.
+func (p *RedisMachineIDProvider) RenewEvery() time.Duration {
+	return p.TTL / 3
+}
+
+// Renew implements LeaseRenewer, refreshing the lease TTL via PEXPIRE.
+func (p *RedisMachineIDProvider) Renew(ctx context.Context) error {
+	reply, err := p.command("PEXPIRE", p.key, fmt.Sprintf("%d", p.TTL.Milliseconds()))
+	if err != nil {
+		return err
+	}
+	if reply != "1" {
+		return fmt.Errorf("goflakeid: redis lease key %s no longer exists", p.key)
+	}
+	return nil
+}
+
+// setNX issues "SET key 1 PX <ttl-ms> NX" and reports whether it won the key.
+func (p *RedisMachineIDProvider) setNX(key string, ttl time.Duration) (bool, error) {
+	reply, err := p.command("SET", key, "1", "PX", fmt.Sprintf("%d", ttl.Milliseconds()), "NX")
+	if err != nil {
+		return false, err
+	}
+	return reply != "", nil
+}
+
+func (p *RedisMachineIDProvider) del(key string) {
+	p.command("DEL", key)
+}
+
+// command sends a RESP array command and returns the simple string, bulk
+// string, or integer reply body, or "" for a nil reply. It holds connMu for the full
+// request-response round trip, so a concurrent call (e.g. Renew racing a
+// release-triggered del) waits rather than interleaving reads/writes on
+// the shared conn.
+func (p *RedisMachineIDProvider) command(args ...string) (string, error) {
+	p.connMu.Lock()
+	defer p.connMu.Unlock()
+
+	req := fmt.Sprintf("*%d\r\n", len(args))
+	for _, a := range args {
+		req += fmt.Sprintf("$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := p.conn.Write([]byte(req)); err != nil {
+		return "", fmt.Errorf("goflakeid: writing redis command: %w", err)
+	}
+
+	r := bufio.NewReader(p.conn)
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("goflakeid: reading redis reply: %w", err)
+	}
+
+	switch line[0] {
+	case '+': // simple string
+		return line[1 : len(line)-2], nil
+	case ':': // integer
+		return line[1 : len(line)-2], nil
+	case '-': // error
+		return "", fmt.Errorf("goflakeid: redis error: %s", line[1:len(line)-2])
+	case '$': // bulk string
+		if line[1:len(line)-2] == "-1" {
+			return "", nil
+		}
+		body, err := r.ReadString('\n')
+		if err != nil {
+			return "", fmt.Errorf("goflakeid: reading redis bulk reply: %w", err)
+		}
+		return body[:len(body)-2], nil
+	default:
+		return "", fmt.Errorf("goflakeid: unexpected redis reply type %q", line[0])
+	}
+}
@@ -0,0 +1,277 @@
+package goflakeid
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ZKMachineIDProvider allocates a machine ID using the classic ZooKeeper
+// ephemeral-sequential recipe (the same building block as ZK leader
+// election and barriers): each process creates an ephemeral, sequential
+// child of a shared parent znode, and takes the sequence number ZooKeeper
+// assigned that child (mod 2^bits) as the machine ID. Because the child
+// is ephemeral, a crashed process's id is freed automatically when its
+// session expires — no explicit TTL bookkeeping is needed, and Renew just
+// keeps the session's ping flowing.
+//
+// The machine ID comes from the node's own assigned sequence number, not
+// from its position in a getChildren() listing: a child's rank among its
+// siblings shifts every time an earlier sibling's ephemeral node is
+// removed, so two processes can briefly compute the same rank for
+// themselves — exactly the collision this provider exists to prevent. The
+// sequence number, in contrast, is assigned once by ZooKeeper at create
+// time and never reassigned to another client.
+//
+// This speaks a minimal subset of the ZooKeeper wire protocol directly
+// (connect, create, ping) rather than depending on a ZooKeeper client
+// module; it does not support ACLs, watches, or reconnection after a
+// dropped TCP connection.
+type ZKMachineIDProvider struct {
+	Addr       string        // host:port of a ZooKeeper server
+	ParentPath string        // defaults to "/goflakeid/machines"
+	Timeout    time.Duration // session timeout; defaults to 10s
+
+	conn     net.Conn
+	xid      int32
+	nodePath string
+}
+
+const (
+	zkOpCreate = 1
+	zkOpPing   = 11
+
+	zkErrOK         = 0
+	zkErrNodeExists = -110
+
+	zkFlagPersistent        = 0
+	zkFlagEphemeralSequence = 3
+)
+
+// Acquire implements MachineIDProvider.
+func (p *ZKMachineIDProvider) Acquire(ctx context.Context, bits uint8) (uint8, func(), error) {
+	if p.ParentPath == "" {
+		p.ParentPath = "/goflakeid/machines"
+	}
+	if p.Timeout == 0 {
+		p.Timeout = 10 * time.Second
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", p.Addr)
+	if err != nil {
+		return 0, nil, fmt.Errorf("goflakeid: dialing zookeeper: %w", err)
+	}
+	p.conn = conn
+
+	if err := p.connect(); err != nil {
+		conn.Close()
+		return 0, nil, err
+	}
+
+	if err := p.create(p.ParentPath, zkFlagPersistent); err != nil && !isZKNodeExists(err) {
+		conn.Close()
+		return 0, nil, err
+	}
+
+	nodePath, err := p.createSequential(p.ParentPath+"/m-", zkFlagEphemeralSequence)
+	if err != nil {
+		conn.Close()
+		return 0, nil, err
+	}
+	p.nodePath = nodePath
+
+	seq, err := parseZKSequence(nodePath)
+	if err != nil {
+		conn.Close()
+		return 0, nil, err
+	}
+
+	release := func() { conn.Close() } // session close deletes the ephemeral node
+	return uint8(seq % (1 << bits)), release, nil
+}
+
+// parseZKSequence extracts the 10-digit sequence number ZooKeeper appends
+// to a sequential node's name (e.g. "/goflakeid/machines/m-0000000007" ->
+// 7).
+func parseZKSequence(nodePath string) (int, error) {
+	idx := strings.LastIndexByte(nodePath, '-')
+	if idx < 0 || idx+1 >= len(nodePath) {
+		return 0, fmt.Errorf("goflakeid: created node %s has no sequence suffix", nodePath)
+	}
+	digits := nodePath[idx+1:]
+	seq, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, fmt.Errorf("goflakeid: created node %s has a non-numeric sequence suffix: %w", nodePath, err)
+	}
+	return seq, nil
+}
+
+// RenewEvery implements LeaseRenewer.
+func (p *ZKMachineIDProvider) RenewEvery() time.Duration {
+	return p.Timeout / 3
+}
+
+// Renew implements LeaseRenewer by pinging the session to keep it alive;
+// ZooKeeper expires the session (and the ephemeral node with it) if pings
+// stop arriving within Timeout.
+func (p *ZKMachineIDProvider) Renew(ctx context.Context) error {
+	_, err := p.request(zkOpPing, nil)
+	return err
+}
+
+func isZKNodeExists(err error) bool {
+	return err != nil && strings.Contains(err.Error(), fmt.Sprintf("error code %d", zkErrNodeExists))
+}
+
+// connect performs the ZooKeeper connect handshake (not request/response
+// framed the way normal ops are: no xid, no opcode).
+func (p *ZKMachineIDProvider) connect() error {
+	var body []byte
+	body = appendInt32(body, 0) // protocol version
+	body = appendInt64(body, 0) // last zxid seen
+	body = appendInt32(body, int32(p.Timeout.Milliseconds()))
+	body = appendInt64(body, 0)   // session id
+	body = appendBytes(body, nil) // password
+	body = append(body, 0)        // readOnly = false
+
+	if err := p.writeFramed(body); err != nil {
+		return fmt.Errorf("goflakeid: sending zookeeper connect request: %w", err)
+	}
+	if _, err := p.readFramed(); err != nil {
+		return fmt.Errorf("goflakeid: reading zookeeper connect response: %w", err)
+	}
+	return nil
+}
+
+// create issues a non-sequential create and discards the resulting path.
+func (p *ZKMachineIDProvider) create(path string, flags int32) error {
+	_, err := p.createSequentialOrNot(path, flags)
+	return err
+}
+
+// createSequential issues a sequential create and returns the resulting path.
+func (p *ZKMachineIDProvider) createSequential(pathPrefix string, flags int32) (string, error) {
+	return p.createSequentialOrNot(pathPrefix, flags)
+}
+
+func (p *ZKMachineIDProvider) createSequentialOrNot(path string, flags int32) (string, error) {
+	var body []byte
+	body = appendString(body, path)
+	body = appendBytes(body, nil) // data
+
+	// ACL: a single world:anyone ALL entry (ZOO_OPEN_ACL_UNSAFE).
+	body = appendInt32(body, 1)
+	body = appendInt32(body, 0x1f)
+	body = appendString(body, "world")
+	body = appendString(body, "anyone")
+
+	body = appendInt32(body, flags)
+
+	resp, err := p.request(zkOpCreate, body)
+	if err != nil {
+		return "", err
+	}
+	_, createdPath := readString(resp, 0)
+	return createdPath, nil
+}
+
+// request sends a framed (xid, opcode, body) request and returns the
+// response body with its (zxid, err) reply header already validated and
+// stripped.
+func (p *ZKMachineIDProvider) request(opcode int32, payload []byte) ([]byte, error) {
+	p.xid++
+	var req []byte
+	req = appendInt32(req, p.xid)
+	req = appendInt32(req, opcode)
+	req = append(req, payload...)
+
+	if err := p.writeFramed(req); err != nil {
+		return nil, fmt.Errorf("goflakeid: sending zookeeper request: %w", err)
+	}
+
+	resp, err := p.readFramed()
+	if err != nil {
+		return nil, fmt.Errorf("goflakeid: reading zookeeper response: %w", err)
+	}
+	if len(resp) < 16 {
+		return nil, fmt.Errorf("goflakeid: zookeeper response too short")
+	}
+	errCode := int32(binary.BigEndian.Uint32(resp[12:16]))
+	if errCode != zkErrOK {
+		return nil, fmt.Errorf("goflakeid: zookeeper error code %d", errCode)
+	}
+	return resp[16:], nil
+}
+
+func (p *ZKMachineIDProvider) writeFramed(body []byte) error {
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(body)))
+	if _, err := p.conn.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := p.conn.Write(body)
+	return err
+}
+
+func (p *ZKMachineIDProvider) readFramed() ([]byte, error) {
+	var header [4]byte
+	if _, err := readFull(p.conn, header[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(header[:])
+	buf := make([]byte, n)
+	if _, err := readFull(p.conn, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func readFull(conn net.Conn, buf []byte) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := conn.Read(buf[total:])
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func appendInt32(b []byte, v int32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(v))
+	return append(b, buf[:]...)
+}
+
+func appendInt64(b []byte, v int64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(v))
+	return append(b, buf[:]...)
+}
+
+func appendBytes(b []byte, v []byte) []byte {
+	if v == nil {
+		return appendInt32(b, -1)
+	}
+	b = appendInt32(b, int32(len(v)))
+	return append(b, v...)
+}
+
+func appendString(b []byte, s string) []byte {
+	return appendBytes(b, []byte(s))
+}
+
+// readString reads a jute length-prefixed string starting at offset and
+// returns the offset just past it along with the decoded string.
+func readString(b []byte, offset int) (int, string) {
+	n := int32(binary.BigEndian.Uint32(b[offset : offset+4]))
+	offset += 4
+	s := string(b[offset : offset+int(n)])
+	return offset + int(n), s
+}
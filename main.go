@@ -3,10 +3,12 @@
 package goflakeid
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
 	"os"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -68,6 +70,21 @@ type Config struct {
 	Epoch        time.Time
 	BitLayout    BitLayout
 	MachineIDGen func() uint8 // Optional auto-generation function
+
+	// ClockPolicy controls how backward clock jumps are handled; it
+	// defaults to PolicyError.
+	ClockPolicy ClockPolicy
+	// MaxWait bounds how long PolicyWait will sleep for a single
+	// regression; it defaults to DefaultMaxWait.
+	MaxWait time.Duration
+	// StateStore, if set, persists a high-water timestamp mark so
+	// NewGenerator can detect a clock regression across restarts.
+	StateStore StateStore
+
+	// MachineIDProvider, if set, takes priority over MachineIDGen and
+	// coordinates machine ID allocation across a fleet via an external
+	// service (Redis, etcd, ZooKeeper, ...).
+	MachineIDProvider MachineIDProvider
 }
 
 // Validate ensures configuration is valid
@@ -113,6 +130,33 @@ func (c *Config) WithBitLayout(layout BitLayout) *Config {
 	return c
 }
 
+// WithClockPolicy sets how the generator reacts to backward clock jumps.
+func (c *Config) WithClockPolicy(policy ClockPolicy) *Config {
+	c.ClockPolicy = policy
+	return c
+}
+
+// WithMaxWait sets the maximum regression PolicyWait will sleep through.
+func (c *Config) WithMaxWait(maxWait time.Duration) *Config {
+	c.MaxWait = maxWait
+	return c
+}
+
+// WithStateStore sets the StateStore used to persist and check the
+// generator's timestamp high-water mark across restarts.
+func (c *Config) WithStateStore(store StateStore) *Config {
+	c.StateStore = store
+	return c
+}
+
+// WithMachineIDProvider configures coordinated machine ID allocation via
+// an external service; see MachineIDProvider. It takes priority over
+// WithAutoMachineID.
+func (c *Config) WithMachineIDProvider(provider MachineIDProvider) *Config {
+	c.MachineIDProvider = provider
+	return c
+}
+
 // WithAutoMachineID enables automatic machine ID generation
 func (c *Config) WithAutoMachineID() *Config {
 	c.MachineIDGen = DefaultMachineIDGenerator
@@ -137,9 +181,23 @@ type Generator struct {
 	
 	maxSequence uint64
 	maxTimestamp uint64
-	
+
 	// Component masks
 	sequenceMask uint64
+
+	// Clock regression handling
+	policy       ClockPolicy
+	maxWait      time.Duration
+	borrowShift  uint8  // PolicyBorrow: bit position of the overflow-epoch parity bit, 0 if unused
+	borrowField  uint64 // PolicyBorrow: mask covering both the parity bit and the sequence below it
+	logicalClock atomic.Uint64
+	stateStore   StateStore
+
+	// MachineIDProvider coordination
+	leaseRelease func()
+	leaseCancel  context.CancelFunc
+	errCh        chan error
+	closeOnce    sync.Once
 }
 
 // NewGenerator creates a new ID generator with validation
@@ -148,13 +206,33 @@ func NewGenerator(config Config) (*Generator, error) {
 	if config.MachineIDGen != nil && config.MachineID == 0 {
 		config.MachineID = config.MachineIDGen()
 	}
-	
+
+	// A MachineIDProvider takes priority over both the above, since it
+	// coordinates uniqueness across the fleet rather than guessing at it.
+	var leaseRelease func()
+	var leaseCancel context.CancelFunc
+	var errCh chan error
+	if config.MachineIDProvider != nil {
+		id, release, cancel, ch, err := acquireMachineID(config.MachineIDProvider, config.BitLayout.MachineBits)
+		if err != nil {
+			return nil, err
+		}
+		config.MachineID = id
+		leaseRelease, leaseCancel, errCh = release, cancel, ch
+	}
+
 	if err := config.Validate(); err != nil {
+		if leaseCancel != nil {
+			leaseCancel()
+		}
+		if leaseRelease != nil {
+			leaseRelease()
+		}
 		return nil, err
 	}
-	
+
 	layout := config.BitLayout
-	
+
 	// Calculate shifts
 	timestampShift := layout.RegionBits + layout.AppBits + layout.MachineBits + layout.SequenceBits
 	regionShift := layout.AppBits + layout.MachineBits + layout.SequenceBits
@@ -164,7 +242,22 @@ func NewGenerator(config Config) (*Generator, error) {
 	// Calculate masks
 	maxSequence := uint64((1 << layout.SequenceBits) - 1)
 	maxTimestamp := uint64((1 << layout.TimestampBits) - 1)
-	
+
+	var borrowShift uint8
+	var borrowField uint64
+	if config.ClockPolicy == PolicyBorrow && layout.SequenceBits > 0 {
+		// Steal the top bit of the sequence field for the overflow-epoch
+		// parity flag, halving the usable per-millisecond budget.
+		borrowShift = layout.SequenceBits - 1
+		borrowField = uint64(1<<layout.SequenceBits) - 1
+		maxSequence = (1 << borrowShift) - 1
+	}
+
+	maxWait := config.MaxWait
+	if maxWait == 0 {
+		maxWait = DefaultMaxWait
+	}
+
 	g := &Generator{
 		config:         config,
 		timestampShift: timestampShift,
@@ -174,54 +267,133 @@ func NewGenerator(config Config) (*Generator, error) {
 		maxSequence:    maxSequence,
 		maxTimestamp:   maxTimestamp,
 		sequenceMask:   maxSequence,
+		policy:         config.ClockPolicy,
+		maxWait:        maxWait,
+		borrowShift:    borrowShift,
+		borrowField:    borrowField,
+		stateStore:     config.StateStore,
+		leaseRelease:   leaseRelease,
+		leaseCancel:    leaseCancel,
+		errCh:          errCh,
 	}
-	
+
 	// Initialize state with current timestamp
 	now := time.Now().UnixMilli() - config.Epoch.UnixMilli()
+	if config.StateStore != nil {
+		hwm, err := config.StateStore.Load()
+		if err != nil {
+			return nil, err
+		}
+		if hwm > uint64(now) {
+			return nil, fmt.Errorf("%w: persisted high-water mark %d is newer than current time %d", ErrClockBackwards, hwm, now)
+		}
+	}
 	initialState := uint64(now) << 22 // 22 = sequence(10) + machine(5) + app(3) + region(4)
 	g.state.Store(initialState)
-	
+
 	return g, nil
 }
 
-// Generate creates a new unique ID using lock-free atomic operations
+// Generate creates a new unique ID using lock-free atomic operations. How
+// a backward clock jump is handled is controlled by Config.ClockPolicy;
+// see PolicyError, PolicyWait, PolicyBorrow, and PolicyLogical.
 func (g *Generator) Generate() (uint64, error) {
 	for {
-		// Get current time
+		// Get current time, substituting the logical clock if PolicyLogical
+		// has advanced it ahead of a wall clock that hasn't caught up yet.
 		now := time.Now().UnixMilli() - g.config.Epoch.UnixMilli()
+		if g.policy == PolicyLogical {
+			if logical := g.logicalClock.Load(); int64(logical) > now {
+				now = int64(logical)
+			}
+		}
 		if now < 0 {
 			return 0, fmt.Errorf("%w: epoch is in the future", ErrClockBackwards)
 		}
 		if uint64(now) > g.maxTimestamp {
 			return 0, fmt.Errorf("timestamp exceeds %d bits", g.config.BitLayout.TimestampBits)
 		}
-		
+
 		// Load current state
 		oldState := g.state.Load()
 		oldTimestamp := oldState >> 22
-		oldSequence := oldState & g.sequenceMask
-		
-		var newSequence uint64
+
+		var oldSequence, oldParity uint64
+		if g.policy == PolicyBorrow {
+			oldLow := oldState & g.borrowField
+			oldParity = (oldLow >> g.borrowShift) & 1
+			oldSequence = oldLow & g.maxSequence
+		} else {
+			oldSequence = oldState & g.sequenceMask
+		}
+
+		var newSequence, newParity uint64
 		newTimestamp := uint64(now)
-		
+
 		// Calculate new sequence
-		if newTimestamp == oldTimestamp {
+		switch {
+		case newTimestamp > oldTimestamp:
+			newSequence = 0
+		case newTimestamp == oldTimestamp:
+			newParity = oldParity
 			newSequence = oldSequence + 1
 			if newSequence > g.maxSequence {
 				// Wait for next millisecond
 				time.Sleep(time.Microsecond)
 				continue
 			}
-		} else if newTimestamp > oldTimestamp {
-			newSequence = 0
-		} else {
-			// Clock moved backwards
-			return 0, ErrClockBackwards
+		case g.policy == PolicyBorrow && oldParity == 1:
+			// The clock is still behind a timestamp an earlier caller
+			// already pinned and flipped parity on. Stay pinned and keep
+			// incrementing the sequence rather than re-entering the
+			// regression branch below and re-flipping parity, which is
+			// what let concurrent regressions collide.
+			newTimestamp = oldTimestamp
+			newParity = 1
+			newSequence = oldSequence + 1
+			if newSequence > g.maxSequence {
+				time.Sleep(time.Microsecond)
+				continue
+			}
+		default:
+			// Clock moved backwards; how we react depends on ClockPolicy.
+			switch g.policy {
+			case PolicyWait:
+				gap := time.Duration(oldTimestamp-newTimestamp) * time.Millisecond
+				if gap > g.maxWait {
+					return 0, fmt.Errorf("%w: regression of %s exceeds MaxWait %s", ErrClockBackwards, gap, g.maxWait)
+				}
+				time.Sleep(gap + time.Millisecond)
+				continue
+			case PolicyBorrow:
+				// Reuse the last timestamp and flip the overflow-epoch
+				// parity bit, derived here from oldParity so it becomes
+				// part of the very state word the CAS below claims —
+				// concurrent losers retry and observe this flip (the
+				// pinned case above) instead of flipping it again.
+				newTimestamp = oldTimestamp
+				newSequence = 0
+				newParity = 1
+			case PolicyLogical:
+				// Advance the logical clock one tick past the last
+				// timestamp used; the wall clock will catch up and stop
+				// being overridden once it passes this value.
+				logical := oldTimestamp + 1
+				g.logicalClock.Store(logical)
+				newTimestamp = logical
+				newSequence = 0
+			default:
+				return 0, ErrClockBackwards
+			}
 		}
-		
+
 		// Build new state
-		newState := (newTimestamp << 22) | newSequence
-		
+		newLow := newSequence
+		if g.policy == PolicyBorrow {
+			newLow = (newParity << g.borrowShift) | newSequence
+		}
+		newState := (newTimestamp << 22) | newLow
+
 		// Try to update state atomically
 		if g.state.CompareAndSwap(oldState, newState) {
 			// Successfully updated, build the ID
@@ -229,8 +401,8 @@ func (g *Generator) Generate() (uint64, error) {
 				(uint64(g.config.RegionID) << g.regionShift) |
 				(uint64(g.config.AppID) << g.appShift) |
 				(uint64(g.config.MachineID) << g.machineShift) |
-				newSequence
-			
+				newLow
+
 			return id, nil
 		}
 		// Another goroutine updated the state, retry
@@ -0,0 +1,160 @@
+package goflakeid
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Reserve atomically claims up to n consecutive sequence numbers within the
+// current millisecond using a single CompareAndSwap, rather than looping
+// Generate n times. The claimed count is clamped to whatever remains of the
+// millisecond's sequence budget, so callers should check the returned count
+// against n and call Reserve again if they need more. ts is the
+// epoch-relative millisecond the reservation was made in; combine it with
+// start/count via the generator's bit layout (see buildIDs) to materialize
+// IDs without any further synchronization.
+//
+// A backward clock jump is handled according to Config.ClockPolicy, the same
+// as Generate, with one exception: PolicyBorrow is not supported here and
+// returns ErrClockBackwards. That policy marks individual IDs with a parity
+// bit as they're minted one at a time; a contiguous range of sequence
+// numbers claimed in bulk has nowhere to carry a per-ID parity bit, so
+// generators configured with PolicyBorrow should use Generate instead of
+// Reserve/Stream/StreamBatches.
+func (g *Generator) Reserve(n int) (start uint64, count int, ts uint64, err error) {
+	if n <= 0 {
+		return 0, 0, 0, fmt.Errorf("n must be positive")
+	}
+
+	for {
+		now := time.Now().UnixMilli() - g.config.Epoch.UnixMilli()
+		if g.policy == PolicyLogical {
+			if logical := g.logicalClock.Load(); int64(logical) > now {
+				now = int64(logical)
+			}
+		}
+		if now < 0 {
+			return 0, 0, 0, fmt.Errorf("%w: epoch is in the future", ErrClockBackwards)
+		}
+		if uint64(now) > g.maxTimestamp {
+			return 0, 0, 0, fmt.Errorf("timestamp exceeds %d bits", g.config.BitLayout.TimestampBits)
+		}
+
+		oldState := g.state.Load()
+		oldTimestamp := oldState >> 22
+		oldSequence := oldState & g.sequenceMask
+
+		var newTimestamp, reserveStart, newSequence uint64
+		newTimestamp = uint64(now)
+
+		switch {
+		case newTimestamp == oldTimestamp:
+			if oldSequence >= g.maxSequence {
+				time.Sleep(time.Microsecond)
+				continue
+			}
+			reserveStart = oldSequence + 1
+		case newTimestamp > oldTimestamp:
+			reserveStart = 0
+		default:
+			// Clock moved backwards; how we react depends on ClockPolicy.
+			switch g.policy {
+			case PolicyWait:
+				gap := time.Duration(oldTimestamp-newTimestamp) * time.Millisecond
+				if gap > g.maxWait {
+					return 0, 0, 0, fmt.Errorf("%w: regression of %s exceeds MaxWait %s", ErrClockBackwards, gap, g.maxWait)
+				}
+				time.Sleep(gap + time.Millisecond)
+				continue
+			case PolicyLogical:
+				logical := oldTimestamp + 1
+				g.logicalClock.Store(logical)
+				newTimestamp = logical
+				reserveStart = 0
+			default:
+				// PolicyBorrow and PolicyError (or unset) both fail
+				// closed: see the doc comment above for why Reserve
+				// can't honor PolicyBorrow's per-ID parity scheme.
+				return 0, 0, 0, ErrClockBackwards
+			}
+		}
+
+		available := g.maxSequence - reserveStart + 1
+		claimed := uint64(n)
+		if claimed > available {
+			claimed = available
+		}
+		newSequence = reserveStart + claimed - 1
+
+		newState := (newTimestamp << 22) | newSequence
+		if g.state.CompareAndSwap(oldState, newState) {
+			return reserveStart, int(claimed), newTimestamp, nil
+		}
+		// Another goroutine updated the state, retry.
+	}
+}
+
+// buildIDs materializes the IDs covered by a Reserve call; it performs no
+// synchronization of its own.
+func (g *Generator) buildIDs(ts, start uint64, count int) []uint64 {
+	ids := make([]uint64, count)
+	base := (ts << g.timestampShift) |
+		(uint64(g.config.RegionID) << g.regionShift) |
+		(uint64(g.config.AppID) << g.appShift) |
+		(uint64(g.config.MachineID) << g.machineShift)
+	for i := 0; i < count; i++ {
+		ids[i] = base | (start + uint64(i))
+	}
+	return ids
+}
+
+// Stream starts a background goroutine that continuously reserves the
+// largest available slice of the current millisecond's sequence budget and
+// feeds the resulting IDs into a buffered channel of size bufSize. The
+// goroutine exits and closes the channel when ctx is canceled, making this
+// safe to use as a long-lived, high-throughput producer: a single Stream
+// can saturate a millisecond's sequence budget without each consumer
+// re-entering the CAS loop in Reserve.
+func (g *Generator) Stream(ctx context.Context, bufSize int) <-chan uint64 {
+	out := make(chan uint64, bufSize)
+	go func() {
+		defer close(out)
+		for {
+			start, count, ts, err := g.Reserve(int(g.maxSequence) + 1)
+			if err != nil {
+				return
+			}
+			for _, id := range g.buildIDs(ts, start, count) {
+				select {
+				case out <- id:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// StreamBatches is like Stream but delivers whole reserved slices as
+// []uint64, for callers that prefer to consume batches rather than
+// individual IDs.
+func (g *Generator) StreamBatches(ctx context.Context, batchSize int) <-chan []uint64 {
+	out := make(chan []uint64, 1)
+	go func() {
+		defer close(out)
+		for {
+			start, count, ts, err := g.Reserve(batchSize)
+			if err != nil {
+				return
+			}
+			select {
+			case out <- g.buildIDs(ts, start, count):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,157 @@
+package goflakeid
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestReserveHonorsPolicyWait checks that Reserve absorbs a small backward
+// clock step under PolicyWait by sleeping past it, the same as Generate,
+// instead of unconditionally returning ErrClockBackwards.
+func TestReserveHonorsPolicyWait(t *testing.T) {
+	g, err := NewGenerator(*NewConfig(1, 1, 1).
+		WithClockPolicy(PolicyWait).
+		WithMaxWait(time.Second))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+
+	now := uint64(time.Now().UnixMilli()-g.config.Epoch.UnixMilli()) + 2
+	g.state.Store(now << 22)
+
+	start, count, ts, err := g.Reserve(4)
+	if err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+	if ts < now {
+		t.Fatalf("Reserve returned ts %d before the pinned state timestamp %d", ts, now)
+	}
+	if count <= 0 {
+		t.Fatalf("Reserve claimed %d, want > 0", count)
+	}
+	_ = start
+}
+
+// TestReserveRejectsPolicyBorrow documents that Reserve cannot honor
+// PolicyBorrow (a contiguous range has nowhere to carry a per-ID parity
+// bit) and fails closed rather than silently misbehaving.
+func TestReserveRejectsPolicyBorrow(t *testing.T) {
+	g, err := NewGenerator(*NewConfig(1, 1, 1).WithClockPolicy(PolicyBorrow))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+
+	future := uint64(time.Now().UnixMilli()-g.config.Epoch.UnixMilli()) + 10_000
+	g.state.Store(future << 22)
+
+	if _, _, _, err := g.Reserve(1); err == nil {
+		t.Fatalf("Reserve under PolicyBorrow with a regressed clock: got nil error, want ErrClockBackwards")
+	}
+}
+
+func TestBuildIDsMaterializesReservedRange(t *testing.T) {
+	g, err := NewGenerator(*NewConfig(2, 3, 5))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+
+	const ts, start, count = 12345, 7, 4
+	ids := g.buildIDs(ts, start, count)
+	if len(ids) != count {
+		t.Fatalf("buildIDs returned %d ids, want %d", len(ids), count)
+	}
+	for i, id := range ids {
+		c := g.Decode(id)
+		if c.RegionID != 2 || c.AppID != 3 || c.MachineID != 5 {
+			t.Fatalf("buildIDs[%d] decoded components = %+v, want region=2 app=3 machine=5", i, c)
+		}
+		if uint64(c.Sequence) != start+uint64(i) {
+			t.Fatalf("buildIDs[%d] sequence = %d, want %d", i, c.Sequence, start+uint64(i))
+		}
+	}
+}
+
+func TestStreamDeliversUniqueIDsAndRespectsCancellation(t *testing.T) {
+	g, err := NewGenerator(*NewConfig(1, 1, 1))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := g.Stream(ctx, 16)
+
+	seen := make(map[uint64]bool)
+	for i := 0; i < 500; i++ {
+		id := <-out
+		if seen[id] {
+			t.Fatalf("Stream delivered duplicate id %d", id)
+		}
+		seen[id] = true
+	}
+
+	cancel()
+	// The channel must close once the background goroutine observes
+	// ctx's cancellation, draining whatever was already buffered.
+	for range out {
+	}
+}
+
+func TestStreamBatchesDeliversClaimedIDs(t *testing.T) {
+	g, err := NewGenerator(*NewConfig(1, 1, 1))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := g.StreamBatches(ctx, 8)
+
+	seen := make(map[uint64]bool)
+	got := 0
+	for got < 100 {
+		batch := <-out
+		if len(batch) == 0 {
+			t.Fatalf("StreamBatches delivered an empty batch")
+		}
+		for _, id := range batch {
+			if seen[id] {
+				t.Fatalf("StreamBatches delivered duplicate id %d", id)
+			}
+			seen[id] = true
+		}
+		got += len(batch)
+	}
+}
+
+// BenchmarkGenerateLoop establishes the baseline this package's Reserve
+// API (via Stream) is meant to beat: one CAS per ID.
+func BenchmarkGenerateLoop(b *testing.B) {
+	g, err := NewGenerator(*NewConfig(1, 1, 1))
+	if err != nil {
+		b.Fatalf("NewGenerator: %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := g.Generate(); err != nil {
+			b.Fatalf("Generate: %v", err)
+		}
+	}
+}
+
+// BenchmarkStream measures Stream's throughput, which batches many IDs
+// per CAS via Reserve instead of looping Generate one CAS at a time.
+func BenchmarkStream(b *testing.B) {
+	g, err := NewGenerator(*NewConfig(1, 1, 1))
+	if err != nil {
+		b.Fatalf("NewGenerator: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := g.Stream(ctx, 1024)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		<-out
+	}
+}
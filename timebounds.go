@@ -0,0 +1,51 @@
+package goflakeid
+
+import (
+	"fmt"
+	"time"
+)
+
+// timestampBound returns the smallest or largest uint64 whose timestamp
+// field (top bits, per shift) corresponds to ms, with all lower bits set
+// to fill (0 for a lower bound, all-ones for an upper bound).
+func timestampBound(ms uint64, shift uint8, fill bool) uint64 {
+	id := ms << shift
+	if fill {
+		id |= (uint64(1) << shift) - 1
+	}
+	return id
+}
+
+// MinIDAt returns the smallest ID this generator could have produced at
+// time t: the one with t's timestamp field and every lower bit zeroed.
+// Combined with MaxIDAt, it gives an inclusive [lo, hi] range usable
+// directly as a `WHERE id BETWEEN ? AND ?` SQL predicate, since the
+// timestamp sits in the high bits and the range is monotonic in time.
+func (g *Generator) MinIDAt(t time.Time) uint64 {
+	ms := uint64(t.Sub(g.config.Epoch) / time.Millisecond)
+	return timestampBound(ms, g.timestampShift, false)
+}
+
+// MaxIDAt returns the largest ID this generator could have produced at
+// time t: the one with t's timestamp field and every lower bit set.
+func (g *Generator) MaxIDAt(t time.Time) uint64 {
+	ms := uint64(t.Sub(g.config.Epoch) / time.Millisecond)
+	return timestampBound(ms, g.timestampShift, true)
+}
+
+// Verify decodes id's region/app/machine fields and checks them against
+// this generator's own configuration, so a caller can detect an ID that
+// leaked in from another region, app, or machine than expected.
+func (g *Generator) Verify(id uint64) error {
+	c := g.Decode(id)
+	if c.RegionID != g.config.RegionID {
+		return fmt.Errorf("%w: id region %d does not match generator region %d", ErrInvalidConfig, c.RegionID, g.config.RegionID)
+	}
+	if c.AppID != g.config.AppID {
+		return fmt.Errorf("%w: id app %d does not match generator app %d", ErrInvalidConfig, c.AppID, g.config.AppID)
+	}
+	if c.MachineID != g.config.MachineID {
+		return fmt.Errorf("%w: id machine %d does not match generator machine %d", ErrInvalidConfig, c.MachineID, g.config.MachineID)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package goflakeid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMinMaxIDAtBoundGeneratedIDs(t *testing.T) {
+	g, err := NewGenerator(*NewConfig(1, 1, 1))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+
+	before := time.Now()
+	ids := make([]uint64, 0, 50)
+	for i := 0; i < 50; i++ {
+		id, err := g.Generate()
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	after := time.Now()
+
+	lo := g.MinIDAt(before)
+	hi := g.MaxIDAt(after)
+	for _, id := range ids {
+		if id < lo || id > hi {
+			t.Fatalf("id %d falls outside [MinIDAt(before), MaxIDAt(after)] = [%d, %d]", id, lo, hi)
+		}
+	}
+
+	// lo/hi should agree with the timestamps Decode recovers: every
+	// generated id's decoded timestamp must fall within [before, after].
+	for _, id := range ids {
+		c := g.Decode(id)
+		if c.Timestamp.Before(before.Add(-time.Millisecond)) || c.Timestamp.After(after.Add(time.Millisecond)) {
+			t.Fatalf("decoded timestamp %v for id %d outside [%v, %v]", c.Timestamp, id, before, after)
+		}
+	}
+}
+
+func TestVerifyRejectsForeignComponents(t *testing.T) {
+	g, err := NewGenerator(*NewConfig(1, 2, 3))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+	other, err := NewGenerator(*NewConfig(1, 2, 4))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+
+	id, err := g.Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if err := g.Verify(id); err != nil {
+		t.Fatalf("Verify of g's own id: %v", err)
+	}
+	if err := other.Verify(id); err == nil {
+		t.Fatalf("Verify accepted an id from a different machine")
+	}
+}